@@ -0,0 +1,58 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type inSession struct{}
+
+func (s inSession) String() string { return "In Session" }
+
+// FixMsgIn enforces the same SendingTime(52) skew window logonState.FixMsgIn checks on Logon,
+// symmetrically, for every inbound message once a session is established: a replayed or
+// clock-skewed message is just as much a problem in session as it is at logon, and the check must
+// not increment NextTargetMsgSeqNum so a corrected retry from the counterparty can still succeed.
+// A Logout closes the session's slot against MaxLogonsPerSessionID, the same as Stop does.
+func (s inSession) FixMsgIn(session *session, msg *Message) (nextState sessionState) {
+	if err := checkSendingTimeSkew(session, msg); err != nil {
+		session.log.OnEvent(err.Error())
+		session.logonClosed()
+		return shutdownOnSendingTimeAccuracyProblem(session, msg)
+	}
+
+	if msgType, err := msg.Header.GetBytes(tagMsgType); err == nil && bytes.Equal(msgType, msgTypeLogout) {
+		session.log.OnEventf("Received Logout %s, logging out", msg)
+		session.logonClosed()
+		return latentState{}
+	}
+
+	return s
+}
+
+func (s inSession) Timeout(session *session, e internal.Event) (nextState sessionState) {
+	return s
+}
+
+// Stop closes the session's slot against MaxLogonsPerSessionID before dropping to Latent State,
+// since the counterparty never got to send a Logout of its own.
+func (s inSession) Stop(session *session) (nextState sessionState) {
+	session.logonClosed()
+	return latentState{}
+}
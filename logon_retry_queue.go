@@ -0,0 +1,238 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	logonRetryBaseDelay = 1 * time.Second
+	logonRetryMaxDelay  = 2 * time.Minute
+	logonRetryMaxJitter = 1 * time.Second
+)
+
+// LogonRetryState is the subset of logonRetryQueue's bookkeeping that gets persisted so retries
+// survive a process restart.
+type LogonRetryState struct {
+	Attempt           int
+	ExpectedMsgSeqNum int
+	NextRetryAt       time.Time
+}
+
+// LogonRetryStore is implemented by a MessageStore that can persist a session's LogonRetryState.
+// MessageStore implementations that don't implement it simply keep the retry queue in memory for
+// the lifetime of the process, i.e. retries do not survive a restart.
+type LogonRetryStore interface {
+	SaveLogonRetry(sessionID SessionID, state LogonRetryState) error
+	LoadLogonRetry(sessionID SessionID) (LogonRetryState, bool, error)
+}
+
+// logonRetryQueue pre-positions NextSenderMsgSeqNum with exponential backoff after the
+// counterparty logs us out with a reason that can plausibly be resolved by retrying (currently,
+// SeqNumTooLow), so that whichever reconnect attempt the session's own reconnect loop makes next
+// presents the corrected sequence number instead of the stale one that just got rejected. It does
+// not itself initiate a reconnect or send a Logon — it only decides, ahead of time, what sequence
+// number that next attempt should use.
+type logonRetryQueue struct {
+	session *session
+
+	mu      sync.Mutex
+	state   LogonRetryState
+	timer   *time.Timer
+	stopped bool
+}
+
+// retryQueue returns session's logonRetryQueue, constructing and caching it (loading any
+// persisted LogonRetryState) on first use. Callers must always go through this accessor rather
+// than the logonRetryQueue field directly, since nothing else in the logon handshake constructs
+// one eagerly.
+func (session *session) retryQueue() *logonRetryQueue {
+	if session.logonRetryQueue == nil {
+		session.logonRetryQueue = newLogonRetryQueue(session)
+	}
+
+	return session.logonRetryQueue
+}
+
+func newLogonRetryQueue(session *session) *logonRetryQueue {
+	q := &logonRetryQueue{session: session}
+
+	if store, ok := session.store.(LogonRetryStore); ok {
+		if state, found, err := store.LoadLogonRetry(session.sessionID); err != nil {
+			session.logError(err)
+		} else if found {
+			q.state = state
+		}
+	}
+
+	return q
+}
+
+// ScheduleRetry records a failed logon attempt and arms a timer that, once the backoff delay
+// elapses, advances NextSenderMsgSeqNum to the expected value ahead of whatever reconnect attempt
+// the session's own reconnect loop makes next. It does not itself trigger a reconnect or send a
+// Logon. It is a no-op unless session.LogonAutoAdvanceSeqNum is enabled.
+func (q *logonRetryQueue) ScheduleRetry(reason LogoutReason) {
+	session := q.session
+	if !session.LogonAutoAdvanceSeqNum {
+		return
+	}
+
+	expected, ok := expectedMsgSeqNumFrom(reason)
+	if !ok {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return
+	}
+
+	if max := session.MaxLogonRetryAttempts; max > 0 && q.state.Attempt >= max {
+		q.giveUp()
+		return
+	}
+
+	q.state.Attempt++
+	q.state.ExpectedMsgSeqNum = expected
+
+	delay := logonRetryBackoff(q.state.Attempt)
+	q.state.NextRetryAt = session.now().Add(delay)
+
+	q.persist()
+
+	if observer, ok := session.application.(LogonRetryObserver); ok {
+		observer.OnLogonRetryScheduled(q.state.Attempt, delay, reason)
+	}
+
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+
+	q.timer = time.AfterFunc(delay, func() { q.retry() })
+}
+
+// retry advances NextSenderMsgSeqNum to the sequence number the counterparty told us it expects,
+// so the session's own reconnect loop presents the corrected number on whatever attempt it makes
+// next. It does not itself dial or send a Logon.
+func (q *logonRetryQueue) retry() {
+	q.mu.Lock()
+	expected := q.state.ExpectedMsgSeqNum
+	stopped := q.stopped
+	q.mu.Unlock()
+
+	if stopped || expected <= 0 {
+		return
+	}
+
+	session := q.session
+	if err := session.forceNextSenderMsgSeqNum(expected); err != nil {
+		session.logError(err)
+		return
+	}
+
+	session.log.OnEventf("Advanced next sender message sequence number to %d ahead of next logon retry", expected)
+}
+
+func (q *logonRetryQueue) giveUp() {
+	if observer, ok := q.session.application.(LogonRetryObserver); ok {
+		observer.OnLogonGiveUp(q.state.Attempt)
+	}
+
+	q.reset()
+}
+
+// Reset clears retry state after a successful logon.
+func (q *logonRetryQueue) Reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.reset()
+}
+
+func (q *logonRetryQueue) reset() {
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	q.state = LogonRetryState{}
+	q.persist()
+}
+
+// Stop aborts any pending retry and prevents further retries from being scheduled. It is called
+// when the session is stopped so the queue doesn't fire a retry against a session that is no
+// longer running.
+func (q *logonRetryQueue) Stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.stopped = true
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+}
+
+func (q *logonRetryQueue) persist() {
+	store, ok := q.session.store.(LogonRetryStore)
+	if !ok {
+		return
+	}
+
+	if err := store.SaveLogonRetry(q.session.sessionID, q.state); err != nil {
+		q.session.logError(err)
+	}
+}
+
+// logonRetryBackoff computes the delay before the nth retry: exponential backoff from
+// logonRetryBaseDelay, capped at logonRetryMaxDelay, with up to logonRetryMaxJitter added to
+// avoid every session in a reconnect storm retrying in lockstep.
+func logonRetryBackoff(attempt int) time.Duration {
+	delay := logonRetryBaseDelay
+	for i := 1; i < attempt && delay < logonRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > logonRetryMaxDelay {
+		delay = logonRetryMaxDelay
+	}
+
+	return delay + time.Duration(rand.Int63n(int64(logonRetryMaxJitter)))
+}
+
+// expectedMsgSeqNumFrom extracts the sequence number a retry should advance to from a
+// LogoutReason, if the reason carries one.
+func expectedMsgSeqNumFrom(reason LogoutReason) (int, bool) {
+	switch reason := reason.(type) {
+	case SeqNumTooLow:
+		return reason.Expected, reason.Expected > 0
+	default:
+		return 0, false
+	}
+}
+
+// LogonRetryObserver is an optional interface an Application can implement to be notified of
+// logonRetryQueue progress. Applications that don't care about retry progress can simply omit it.
+type LogonRetryObserver interface {
+	OnLogonRetryScheduled(attempt int, delay time.Duration, reason LogoutReason)
+	OnLogonGiveUp(attempts int)
+}
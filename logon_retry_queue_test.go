@@ -0,0 +1,50 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "testing"
+
+func TestSessionRetryQueueLazyInitNoPanic(t *testing.T) {
+	session := &session{}
+
+	// Reset, ScheduleRetry, and Stop must all be safe to call on a session that never had its
+	// logonRetryQueue constructed for it.
+	session.retryQueue().Reset()
+	session.retryQueue().ScheduleRetry(SeqNumTooLow{Expected: 5})
+	session.retryQueue().Stop()
+
+	if session.retryQueue() == nil {
+		t.Fatal("retryQueue() returned nil")
+	}
+}
+
+func TestLogonRetryBackoffIsBoundedAndGrows(t *testing.T) {
+	first := logonRetryBackoff(1)
+	if first < logonRetryBaseDelay || first >= logonRetryBaseDelay+logonRetryMaxJitter {
+		t.Errorf("logonRetryBackoff(1) = %v, want within [%v, %v)", first, logonRetryBaseDelay, logonRetryBaseDelay+logonRetryMaxJitter)
+	}
+
+	if third := logonRetryBackoff(3); third < 4*logonRetryBaseDelay {
+		t.Errorf("logonRetryBackoff(3) = %v, want >= %v", third, 4*logonRetryBaseDelay)
+	}
+
+	for attempt := 1; attempt <= 20; attempt++ {
+		delay := logonRetryBackoff(attempt)
+		if delay > logonRetryMaxDelay+logonRetryMaxJitter {
+			t.Errorf("logonRetryBackoff(%d) = %v, want <= %v", attempt, delay, logonRetryMaxDelay+logonRetryMaxJitter)
+		}
+	}
+}
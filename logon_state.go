@@ -17,14 +17,10 @@ package quickfix
 
 import (
 	"bytes"
-	"regexp"
-	"strconv"
 
 	"github.com/quickfixgo/quickfix/internal"
 )
 
-var msgSeqNumTooLowRegex = regexp.MustCompile(`MsgSeqNum too low, expecting (\d+) but received \d+`)
-
 type logonState struct{ connectedNotLoggedOn }
 
 func (s logonState) String() string { return "Logon State" }
@@ -47,32 +43,34 @@ func (s logonState) FixMsgIn(session *session, msg *Message) (nextState sessionS
 	if bytes.Equal(msgType, msgTypeLogout) {
 		session.log.OnEventf("Invalid Session State: Received Logout %s while waiting for Logon", msg)
 
-		// Get the reason for logout.
-		reason, err := msg.Body.GetString(tagText)
-		if err != nil {
-			return handleStateError(session, err)
-		}
-
-		// Check if the reason is message sequence number being too low.
-		res := msgSeqNumTooLowRegex.FindStringSubmatch(reason)
-		if res == nil {
-			return latentState{}
-		}
-
-		// Message sequence number is too low.
-		if session.LogonForceSenderMsgSeqNum {
-			// Get the value expected by the target.
-			expectedMsgSeqNum, err := strconv.Atoi(res[1])
-			if err != nil {
-				return handleStateError(session, err)
+		// Classify the reason for logout so we can decide how (and whether) to recover, preferring
+		// structured tags over free-text matching of Text(58).
+		switch reason := s.classifyLogout(session, msg).(type) {
+		case SeqNumTooLow:
+			// Message sequence number is too low. There is no recovering from lost data, so, if
+			// the application wants to, we can force the next sender message sequence number to
+			// be equal to whatever the target expects it to be. Without this, the application
+			// will have to wait until the sender message sequence number naturally matches what
+			// the target is expecting (as a result of retrying logon multiple times) and then the
+			// logon will succeed, but this could take several hours depending on the sequence num
+			// gap. This is almost certainly required in testing where the sender session state is
+			// discarded after testing (assuming the target doesn't support ResetSeqNumFlag 141=Y).
+			if shouldForceSenderMsgSeqNum(session, reason) {
+				session.log.OnEventf("Forcing next sender message sequence number to %d", reason.Expected)
+
+				if err := session.forceNextSenderMsgSeqNum(reason.Expected); err != nil {
+					return handleStateError(session, err)
+				}
+			} else {
+				// Rather than waiting for the plain reconnect timer to fire (possibly several
+				// times) until the sequence numbers happen to align, schedule a backed-off retry
+				// that advances NextSenderMsgSeqNum directly.
+				session.retryQueue().ScheduleRetry(reason)
 			}
 
-			session.log.OnEventf("Forcing next sender message sequence number to %d", expectedMsgSeqNum)
-
-			// Force the next sender message sequence number to be equal to the expected value.
-			if err := session.forceNextSenderMsgSeqNum(expectedMsgSeqNum); err != nil {
-				return handleStateError(session, err)
-			}
+		default:
+			// No recovery path for this category (yet); fall through to latentState{} like any
+			// other unsolicited logout.
 		}
 
 		return latentState{}
@@ -83,8 +81,20 @@ func (s logonState) FixMsgIn(session *session, msg *Message) (nextState sessionS
 		return latentState{}
 	}
 
+	if err := checkSendingTimeSkew(session, msg); err != nil {
+		session.log.OnEvent(err.Error())
+		return shutdownOnSendingTimeAccuracyProblem(session, msg)
+	}
+
+	if reason := session.checkMaxLogons(); reason != nil {
+		return shutdownLogonReject(session, msg, reason)
+	}
+
 	if err := session.handleLogon(msg); err != nil {
 		switch err := err.(type) {
+		case RejectLogonReason:
+			return shutdownLogonReject(session, msg, err)
+
 		case RejectLogon:
 			return shutdownWithReason(session, msg, true, err.Error())
 
@@ -104,6 +114,9 @@ func (s logonState) FixMsgIn(session *session, msg *Message) (nextState sessionS
 		}
 	}
 
+	session.retryQueue().Reset()
+	session.logonAccepted()
+
 	// Notify the app that the session is ready.
 	session.application.InSession(session.sessionID)
 
@@ -120,13 +133,27 @@ func (s logonState) Timeout(session *session, e internal.Event) (nextState sessi
 }
 
 func (s logonState) Stop(session *session) (nextState sessionState) {
+	session.retryQueue().Stop()
 	return latentState{}
 }
 
+// shouldForceSenderMsgSeqNum reports whether a SeqNumTooLow logout received in Logon State should
+// force NextSenderMsgSeqNum directly rather than going through the backed-off retry queue. Pulled
+// out as its own function so the decision can be unit tested without driving the whole state
+// machine; the "only while waiting for Logon" half of the property is enforced structurally, since
+// this helper is only ever called from logonState.FixMsgIn's Logout branch.
+func shouldForceSenderMsgSeqNum(session *session, reason SeqNumTooLow) bool {
+	return session.LogonForceSenderMsgSeqNum && reason.Expected > 0
+}
+
 func shutdownWithReason(session *session, msg *Message, incrNextTargetMsgSeqNum bool, reason string) (nextState sessionState) {
 	session.log.OnEvent(reason)
-	logout := session.buildLogout(reason)
+	return shutdownWithBuiltLogout(session, msg, session.buildLogout(reason), incrNextTargetMsgSeqNum)
+}
 
+// shutdownWithBuiltLogout sends an already-built Logout in reply to msg and drops back to
+// latentState{}, incrementing NextTargetMsgSeqNum only if incrNextTargetMsgSeqNum is true.
+func shutdownWithBuiltLogout(session *session, msg, logout *Message, incrNextTargetMsgSeqNum bool) (nextState sessionState) {
 	if err := session.dropAndSendInReplyTo(logout, msg); err != nil {
 		session.logError(err)
 	}
@@ -0,0 +1,93 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "testing"
+
+// fakeTargetSeqNumStore embeds MessageStore so it satisfies the interface without implementing
+// every method, tracking just the one call shutdownWithBuiltLogout makes.
+type fakeTargetSeqNumStore struct {
+	MessageStore
+	incrTargetCalls int
+}
+
+func (s *fakeTargetSeqNumStore) IncrNextTargetMsgSeqNum() error {
+	s.incrTargetCalls++
+	return nil
+}
+
+func TestShouldForceSenderMsgSeqNum(t *testing.T) {
+	cases := []struct {
+		name     string
+		force    bool
+		expected int
+		want     bool
+	}{
+		{"disabled", false, 5, false},
+		{"enabled with expected", true, 5, true},
+		{"enabled without expected", true, 0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			session := &session{LogonForceSenderMsgSeqNum: c.force}
+			if got := shouldForceSenderMsgSeqNum(session, SeqNumTooLow{Expected: c.expected}); got != c.want {
+				t.Errorf("shouldForceSenderMsgSeqNum(force=%v, expected=%d) = %v, want %v", c.force, c.expected, got, c.want)
+			}
+		})
+	}
+}
+
+// TestRejectLogonReasonsIncrNextTargetMsgSeqNum pins each RejectLogonReason's
+// incrNextTargetMsgSeqNum() contract: shutdownLogonReject must increment NextTargetMsgSeqNum
+// exactly when the reason says to, and never otherwise.
+func TestRejectLogonReasonsIncrNextTargetMsgSeqNum(t *testing.T) {
+	cases := []struct {
+		name   string
+		reason RejectLogonReason
+	}{
+		{"UnknownSession", RejectLogonUnknownSession{}},
+		{"MaxConnectionsReached", RejectLogonMaxConnectionsReached{Max: 1}},
+		{"BadCredentials", RejectLogonBadCredentials{}},
+		{"CompIDMismatch", RejectLogonCompIDMismatch{}},
+		{"EncryptMethodUnsupported", RejectLogonEncryptMethodUnsupported{EncryptMethod: 1}},
+		{"HeartBtIntOutOfRange", RejectLogonHeartBtIntOutOfRange{HeartBtInt: 1, Min: 10, Max: 60}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := &fakeTargetSeqNumStore{}
+			session := &session{store: store, log: fakeNullLog{}}
+
+			shutdownLogonReject(session, NewMessage(), c.reason)
+
+			want := 0
+			if c.reason.incrNextTargetMsgSeqNum() {
+				want = 1
+			}
+			if store.incrTargetCalls != want {
+				t.Errorf("IncrNextTargetMsgSeqNum called %d times, want %d", store.incrTargetCalls, want)
+			}
+		})
+	}
+}
+
+// fakeNullLog embeds Log so it satisfies the interface without implementing every method;
+// OnEvent/OnEventf are the only ones the code under test calls.
+type fakeNullLog struct{ Log }
+
+func (fakeNullLog) OnEvent(string)                  {}
+func (fakeNullLog) OnEventf(string, ...interface{}) {}
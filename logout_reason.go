@@ -0,0 +1,164 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// LogoutReason categorizes why a counterparty logged us out while we were waiting for a Logon
+// response. Prefer matching on this type over scanning Text(58) directly. The default classifier
+// derives it from Text(58) first; NextExpectedMsgSeqNum(789) only yields SeqNumTooLow when Text
+// matches none of the known phrasings, and RefTagID(371), RefMsgType(372) and
+// SessionRejectReason(373) only ever yield Other.
+type LogoutReason interface {
+	isLogoutReason()
+}
+
+// SeqNumTooLow means the counterparty rejected our Logon because our MsgSeqNum was lower than
+// it expected. Expected is the sequence number the counterparty told us it wants next, or 0 if
+// that could not be determined.
+type SeqNumTooLow struct{ Expected int }
+
+// SeqNumTooHigh means the counterparty rejected our Logon because our MsgSeqNum was higher than
+// it expected, i.e. there is a gap in what it has received from us.
+type SeqNumTooHigh struct{}
+
+// PasswordExpired means the counterparty rejected our Logon credentials as expired.
+type PasswordExpired struct{}
+
+// SessionNotFound means the counterparty does not recognize our SessionID.
+type SessionNotFound struct{}
+
+// MaxConnsReached means the counterparty has reached the maximum number of connections it will
+// accept for our SessionID.
+type MaxConnsReached struct{}
+
+// Other is the fallback for any logout Text that none of the registered matchers recognize.
+type Other struct{ Text string }
+
+func (SeqNumTooLow) isLogoutReason()    {}
+func (SeqNumTooHigh) isLogoutReason()   {}
+func (PasswordExpired) isLogoutReason() {}
+func (SessionNotFound) isLogoutReason() {}
+func (MaxConnsReached) isLogoutReason() {}
+func (Other) isLogoutReason()           {}
+
+// LogoutReasonClassifier turns the Text(58) and related tags of an inbound Logout into a
+// LogoutReason. Applications can register their own classifier on a Session to recognize
+// counterparty-specific phrasings (regex, prefix match, SessionRejectReason lookup, etc.)
+// in place of the default QuickFIX/J and QuickFIX-n matchers.
+type LogoutReasonClassifier interface {
+	Classify(msg *Message) LogoutReason
+}
+
+// LogoutReasonClassifierFunc adapts a function to a LogoutReasonClassifier.
+type LogoutReasonClassifierFunc func(msg *Message) LogoutReason
+
+// Classify implements LogoutReasonClassifier.
+func (f LogoutReasonClassifierFunc) Classify(msg *Message) LogoutReason { return f(msg) }
+
+var msgSeqNumTooLowRegex = regexp.MustCompile(`MsgSeqNum too low, expecting (\d+) but received \d+`)
+var msgSeqNumTooHighRegex = regexp.MustCompile(`MsgSeqNum too high`)
+var passwordExpiredRegex = regexp.MustCompile(`(?i)password.*expired`)
+var sessionNotFoundRegex = regexp.MustCompile(`(?i)session.*not found`)
+var maxConnsReachedRegex = regexp.MustCompile(`(?i)max(imum)? .*(sessions|connections)`)
+
+// defaultLogoutReasonClassifier is used whenever a Session has not registered its own
+// LogoutReasonClassifier. It matches the common QuickFIX/J and QuickFIX-n phrasings of Text(58)
+// first, and consults the structured tags (789, then 371/372/373) only when Text matches none of
+// them; structured tags do not take priority over a matching Text.
+var defaultLogoutReasonClassifier = LogoutReasonClassifierFunc(classifyLogoutReason)
+
+func classifyLogoutReason(msg *Message) LogoutReason {
+	text, textErr := msg.Body.GetString(tagText)
+
+	// Text(58) is the most specific signal a counterparty sends, so give it the first look: a
+	// Logout whose Text unambiguously matches one of these phrasings is that reason, even if it
+	// also happens to carry 789 or a reject tag for some unrelated purpose.
+	if textErr == nil {
+		switch {
+		case msgSeqNumTooLowRegex.MatchString(text):
+			res := msgSeqNumTooLowRegex.FindStringSubmatch(text)
+			expected, _ := strconv.Atoi(res[1])
+			return SeqNumTooLow{Expected: expected}
+
+		case msgSeqNumTooHighRegex.MatchString(text):
+			return SeqNumTooHigh{}
+
+		case passwordExpiredRegex.MatchString(text):
+			return PasswordExpired{}
+
+		case sessionNotFoundRegex.MatchString(text):
+			return SessionNotFound{}
+
+		case maxConnsReachedRegex.MatchString(text):
+			return MaxConnsReached{}
+		}
+	}
+
+	// 789 NextExpectedMsgSeqNum is the structured signal for SeqNumTooLow. Only fall back to it
+	// once Text has had a chance to identify a different category, so a counterparty that
+	// includes 789 on a Logout for an unrelated reason doesn't get routed into the retry path.
+	if expected, err := msg.Body.GetInt(tagNextExpectedMsgSeqNum); err == nil {
+		return SeqNumTooLow{Expected: expected}
+	}
+
+	// A Logout carrying RefTagID(371), RefMsgType(372), or SessionRejectReason(373) is referring
+	// to a specific prior message/tag rather than describing itself in Text, which none of our
+	// named categories capture more precisely than Other. Only reached once neither Text nor 789
+	// identified a more specific reason.
+	if reason, ok := classifyFromRejectTags(msg, text, textErr); ok {
+		return reason
+	}
+
+	if textErr != nil {
+		return Other{}
+	}
+
+	return Other{Text: text}
+}
+
+// classifyFromRejectTags reports whether msg carries RefTagID(371), RefMsgType(372), or
+// SessionRejectReason(373) — a structured reference to a specific prior message/tag that none of
+// our five named categories model, so it is surfaced as Other rather than left to Text scanning.
+// Callers must only use this once Text(58) and 789 have already had a chance to classify msg more
+// specifically.
+func classifyFromRejectTags(msg *Message, text string, textErr error) (LogoutReason, bool) {
+	_, refTagIDErr := msg.Body.GetInt(tagRefTagID)
+	_, refMsgTypeErr := msg.Body.GetString(tagRefMsgType)
+	_, sessionRejectReasonErr := msg.Body.GetInt(tagSessionRejectReason)
+
+	if refTagIDErr != nil && refMsgTypeErr != nil && sessionRejectReasonErr != nil {
+		return nil, false
+	}
+
+	if textErr != nil {
+		return Other{}, true
+	}
+
+	return Other{Text: text}, true
+}
+
+func (s logonState) classifyLogout(session *session, msg *Message) LogoutReason {
+	classifier := session.LogoutReasonClassifier
+	if classifier == nil {
+		classifier = defaultLogoutReasonClassifier
+	}
+
+	return classifier.Classify(msg)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "testing"
+
+func logoutMsg(text string) *Message {
+	msg := NewMessage()
+	if text != "" {
+		msg.Body.SetString(tagText, text)
+	}
+	return msg
+}
+
+func TestClassifyLogoutReasonTextOnly(t *testing.T) {
+	tests := []struct {
+		text string
+		want LogoutReason
+	}{
+		{"MsgSeqNum too low, expecting 42 but received 10", SeqNumTooLow{Expected: 42}},
+		{"MsgSeqNum too high, expecting 10 but received 42", SeqNumTooHigh{}},
+		{"Password expired", PasswordExpired{}},
+		{"Session not found", SessionNotFound{}},
+		{"Maximum number of sessions reached", MaxConnsReached{}},
+		{"some unrecognized reason", Other{Text: "some unrecognized reason"}},
+	}
+
+	for _, tt := range tests {
+		got := classifyLogoutReason(logoutMsg(tt.text))
+		if got != tt.want {
+			t.Errorf("classifyLogoutReason(%q) = %#v, want %#v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyLogoutReasonPrefersTextOver789(t *testing.T) {
+	// A counterparty that sends NextExpectedMsgSeqNum(789) alongside a Logout for an unrelated
+	// reason must not be misclassified as SeqNumTooLow.
+	msg := logoutMsg("Maximum number of sessions reached")
+	msg.Body.SetInt(tagNextExpectedMsgSeqNum, 5)
+
+	if got := classifyLogoutReason(msg); got != (MaxConnsReached{}) {
+		t.Errorf("classifyLogoutReason() = %#v, want MaxConnsReached{}", got)
+	}
+}
+
+func TestClassifyLogoutReasonFallsBackTo789(t *testing.T) {
+	msg := logoutMsg("")
+	msg.Body.SetInt(tagNextExpectedMsgSeqNum, 7)
+
+	want := SeqNumTooLow{Expected: 7}
+	if got := classifyLogoutReason(msg); got != want {
+		t.Errorf("classifyLogoutReason() = %#v, want %#v", got, want)
+	}
+}
+
+func TestClassifyLogoutReasonFallsBackToRejectTagsOnlyWhenTextDoesNotMatch(t *testing.T) {
+	// SessionRejectReason(373) alongside Text that doesn't match any registered phrase should
+	// surface as Other.
+	msg := logoutMsg("unrelated free text")
+	msg.Body.SetInt(tagSessionRejectReason, 99)
+
+	want := Other{Text: "unrelated free text"}
+	if got := classifyLogoutReason(msg); got != want {
+		t.Errorf("classifyLogoutReason() = %#v, want %#v", got, want)
+	}
+}
+
+func TestClassifyLogoutReasonPrefersTextOverRejectTags(t *testing.T) {
+	// A counterparty that tags a genuine, recognizable Logout reason with SessionRejectReason(373)
+	// must not be reclassified as Other: the Text phrase match takes priority.
+	msg := logoutMsg("MsgSeqNum too low, expecting 42 but received 10")
+	msg.Body.SetInt(tagSessionRejectReason, 99)
+
+	want := SeqNumTooLow{Expected: 42}
+	if got := classifyLogoutReason(msg); got != want {
+		t.Errorf("classifyLogoutReason() = %#v, want %#v", got, want)
+	}
+}
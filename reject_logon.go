@@ -0,0 +1,147 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "fmt"
+
+// RejectLogonReason is implemented by every typed Logon rejection handleLogon can return. It
+// lets shutdownLogonReject build a spec-compliant Logout for any of them without a growing,
+// duplicated switch over each rejection's Text/SessionRejectReason/incrNextTargetMsgSeqNum.
+type RejectLogonReason interface {
+	error
+
+	logoutText() string
+	sessionRejectReason() (reason int, ok bool)
+	incrNextTargetMsgSeqNum() bool
+}
+
+// RejectLogonUnknownSession is returned by handleLogon when the inbound Logon's SessionID does
+// not match any session this acceptor knows about.
+type RejectLogonUnknownSession struct{ SessionID SessionID }
+
+// RejectLogonMaxConnectionsReached is returned by checkMaxLogons, which logonState.FixMsgIn calls
+// before handleLogon, when accepting this Logon would exceed MaxLogonsPerSessionID.
+type RejectLogonMaxConnectionsReached struct{ Max int }
+
+// RejectLogonBadCredentials is returned by handleLogon when the Logon's credentials are rejected
+// by the application.
+type RejectLogonBadCredentials struct{}
+
+// RejectLogonCompIDMismatch is returned by handleLogon when SenderCompID/TargetCompID on the
+// Logon don't match the configured SessionID.
+type RejectLogonCompIDMismatch struct{}
+
+// RejectLogonEncryptMethodUnsupported is returned by handleLogon when EncryptMethod(98) requests
+// encryption this session doesn't support.
+type RejectLogonEncryptMethodUnsupported struct{ EncryptMethod int }
+
+// RejectLogonHeartBtIntOutOfRange is returned by handleLogon when HeartBtInt(108) falls outside
+// the bounds this session enforces.
+type RejectLogonHeartBtIntOutOfRange struct{ HeartBtInt, Min, Max int }
+
+func (e RejectLogonUnknownSession) Error() string {
+	return fmt.Sprintf("Unknown session: %v", e.SessionID)
+}
+func (e RejectLogonUnknownSession) logoutText() string               { return e.Error() }
+func (e RejectLogonUnknownSession) sessionRejectReason() (int, bool) { return 0, false }
+func (e RejectLogonUnknownSession) incrNextTargetMsgSeqNum() bool    { return true }
+
+func (e RejectLogonMaxConnectionsReached) Error() string {
+	return fmt.Sprintf("Maximum number of sessions (%d) reached", e.Max)
+}
+func (e RejectLogonMaxConnectionsReached) logoutText() string               { return e.Error() }
+func (e RejectLogonMaxConnectionsReached) sessionRejectReason() (int, bool) { return 0, false }
+func (e RejectLogonMaxConnectionsReached) incrNextTargetMsgSeqNum() bool    { return false }
+
+func (e RejectLogonBadCredentials) Error() string                    { return "Bad credentials" }
+func (e RejectLogonBadCredentials) logoutText() string               { return e.Error() }
+func (e RejectLogonBadCredentials) sessionRejectReason() (int, bool) { return 0, false }
+func (e RejectLogonBadCredentials) incrNextTargetMsgSeqNum() bool    { return true }
+
+func (e RejectLogonCompIDMismatch) Error() string      { return "CompID problem" }
+func (e RejectLogonCompIDMismatch) logoutText() string { return e.Error() }
+func (e RejectLogonCompIDMismatch) sessionRejectReason() (int, bool) {
+	return sessionRejectReasonCompIDProblem, true
+}
+func (e RejectLogonCompIDMismatch) incrNextTargetMsgSeqNum() bool { return true }
+
+func (e RejectLogonEncryptMethodUnsupported) Error() string {
+	return fmt.Sprintf("EncryptMethod %d not supported", e.EncryptMethod)
+}
+func (e RejectLogonEncryptMethodUnsupported) logoutText() string { return e.Error() }
+func (e RejectLogonEncryptMethodUnsupported) sessionRejectReason() (int, bool) {
+	return sessionRejectReasonValueOutOfRange, true
+}
+func (e RejectLogonEncryptMethodUnsupported) incrNextTargetMsgSeqNum() bool { return true }
+
+func (e RejectLogonHeartBtIntOutOfRange) Error() string {
+	return fmt.Sprintf("HeartBtInt %d out of range [%d, %d]", e.HeartBtInt, e.Min, e.Max)
+}
+func (e RejectLogonHeartBtIntOutOfRange) logoutText() string { return e.Error() }
+func (e RejectLogonHeartBtIntOutOfRange) sessionRejectReason() (int, bool) {
+	return sessionRejectReasonValueOutOfRange, true
+}
+func (e RejectLogonHeartBtIntOutOfRange) incrNextTargetMsgSeqNum() bool { return true }
+
+const (
+	sessionRejectReasonCompIDProblem   = 9
+	sessionRejectReasonValueOutOfRange = 5
+)
+
+// shutdownLogonReject builds and sends a Logout for a typed RejectLogonReason, setting
+// SessionRejectReason(373) when the reason carries one, and incrementing NextTargetMsgSeqNum
+// according to the FIX rules for that specific rejection.
+func shutdownLogonReject(session *session, msg *Message, reason RejectLogonReason) (nextState sessionState) {
+	logout := session.buildLogout(reason.logoutText())
+	if code, ok := reason.sessionRejectReason(); ok {
+		logout.Body.SetInt(tagSessionRejectReason, code)
+	}
+
+	return shutdownWithBuiltLogout(session, msg, logout, reason.incrNextTargetMsgSeqNum())
+}
+
+// checkMaxLogons returns RejectLogonMaxConnectionsReached if accepting another logon for this
+// session's SessionID would exceed MaxLogonsPerSessionID; otherwise nil. logonCount tracks active
+// (currently logged-on) connections, not logon attempts: it is incremented by logonAccepted once
+// a logon actually succeeds and decremented by logonClosed once that connection leaves In Session
+// (logout, Stop, disconnect), so a counterparty that logs on and off repeatedly can't ratchet this
+// up into rejecting every future logon.
+func (session *session) checkMaxLogons() RejectLogonReason {
+	if session.MaxLogonsPerSessionID <= 0 {
+		return nil
+	}
+
+	if session.logonCount >= session.MaxLogonsPerSessionID {
+		return RejectLogonMaxConnectionsReached{Max: session.MaxLogonsPerSessionID}
+	}
+
+	return nil
+}
+
+// logonAccepted records that a logon succeeded and counts this session against
+// MaxLogonsPerSessionID until logonClosed is called.
+func (session *session) logonAccepted() {
+	session.logonCount++
+}
+
+// logonClosed records that an active logon ended (logout, Stop, disconnect), freeing its slot
+// against MaxLogonsPerSessionID. Safe to call even when no logon was ever accepted for this
+// session, e.g. Stop from Logon State before a logon succeeded.
+func (session *session) logonClosed() {
+	if session.logonCount > 0 {
+		session.logonCount--
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "testing"
+
+func TestCheckMaxLogonsTracksActiveConnectionsNotAttempts(t *testing.T) {
+	session := &session{MaxLogonsPerSessionID: 1}
+
+	if reason := session.checkMaxLogons(); reason != nil {
+		t.Fatalf("checkMaxLogons() = %v, want nil before any logon", reason)
+	}
+
+	session.logonAccepted()
+
+	if reason := session.checkMaxLogons(); reason == nil {
+		t.Fatal("checkMaxLogons() = nil, want RejectLogonMaxConnectionsReached once at capacity")
+	}
+
+	// A second, rejected logon attempt must not further ratchet up logonCount: repeatedly hitting
+	// checkMaxLogons while already at capacity should not change whether the slot frees up once
+	// the one active logon closes.
+	session.checkMaxLogons()
+	session.checkMaxLogons()
+
+	session.logonClosed()
+
+	if reason := session.checkMaxLogons(); reason != nil {
+		t.Fatalf("checkMaxLogons() = %v, want nil after the active logon closed", reason)
+	}
+}
+
+func TestLogonClosedWithoutAcceptedLogonDoesNotGoNegative(t *testing.T) {
+	session := &session{MaxLogonsPerSessionID: 1}
+
+	session.logonClosed()
+
+	if session.logonCount != 0 {
+		t.Fatalf("logonCount = %d, want 0", session.logonCount)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default bounds for how far an inbound message's SendingTime(52) may drift from our own clock
+// before it is rejected. Mirrors the window MTProto uses against replay/clock-drift: messages
+// created too long ago are most likely replayed, and messages claiming a future creation time
+// point at a counterparty whose clock (or ours) is off.
+const (
+	defaultMaxSendingTimeSkewPast   = 300 * time.Second
+	defaultMaxSendingTimeSkewFuture = 30 * time.Second
+
+	sessionRejectReasonSendingTimeAccuracyProblem = 10
+)
+
+// sendingTimeAccuracyProblem is returned when an inbound message's SendingTime(52) falls outside
+// the session's configured skew window.
+type sendingTimeAccuracyProblem struct {
+	sendingTime time.Time
+	now         time.Time
+}
+
+func (e sendingTimeAccuracyProblem) Error() string {
+	return fmt.Sprintf("SendingTime accuracy problem: sendingTime=%s now=%s", e.sendingTime, e.now)
+}
+
+// now returns the session's notion of the current time. Always prefer this over calling
+// time.Now() directly anywhere session behavior depends on the current time, so that tests can
+// inject a fixed or simulated clock.
+func (session *session) now() time.Time {
+	if session.clock != nil {
+		return session.clock()
+	}
+
+	return time.Now()
+}
+
+// checkSendingTimeSkew verifies that msg's SendingTime(52) falls within the session's configured
+// MaxSendingTimeSkewPast/MaxSendingTimeSkewFuture window of session.now(). It is a no-op for
+// sessions that have opted out via SkipSendingTimeSkewCheck.
+func checkSendingTimeSkew(session *session, msg *Message) error {
+	if session.SkipSendingTimeSkewCheck {
+		return nil
+	}
+
+	sendingTime, err := msg.Header.GetTime(tagSendingTime)
+	if err != nil {
+		return err
+	}
+
+	maxPast := session.MaxSendingTimeSkewPast
+	if maxPast <= 0 {
+		maxPast = defaultMaxSendingTimeSkewPast
+	}
+
+	maxFuture := session.MaxSendingTimeSkewFuture
+	if maxFuture <= 0 {
+		maxFuture = defaultMaxSendingTimeSkewFuture
+	}
+
+	now := session.now()
+	skew := now.Sub(sendingTime)
+
+	if skew > maxPast || -skew > maxFuture {
+		return sendingTimeAccuracyProblem{sendingTime: sendingTime, now: now}
+	}
+
+	return nil
+}
+
+// shutdownOnSendingTimeAccuracyProblem rejects msg for a SendingTime accuracy problem without
+// incrementing NextTargetMsgSeqNum, so that a corrected retry from the counterparty can still
+// succeed.
+func shutdownOnSendingTimeAccuracyProblem(session *session, msg *Message) (nextState sessionState) {
+	logout := session.buildLogout("SendingTime accuracy problem")
+	logout.Body.SetInt(tagSessionRejectReason, sessionRejectReasonSendingTimeAccuracyProblem)
+
+	if err := session.dropAndSendInReplyTo(logout, msg); err != nil {
+		session.logError(err)
+	}
+
+	return latentState{}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+	"time"
+)
+
+func sendingTimeMsg(t time.Time) *Message {
+	msg := NewMessage()
+	msg.Header.SetUTCTimestamp(tagSendingTime, t)
+	return msg
+}
+
+func TestCheckSendingTimeSkewWithinWindow(t *testing.T) {
+	now := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	session := &session{clock: func() time.Time { return now }}
+
+	if err := checkSendingTimeSkew(session, sendingTimeMsg(now.Add(-1*time.Minute))); err != nil {
+		t.Errorf("expected no error for message 1m in the past, got %v", err)
+	}
+}
+
+func TestCheckSendingTimeSkewTooFarInPast(t *testing.T) {
+	now := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	session := &session{clock: func() time.Time { return now }}
+
+	if err := checkSendingTimeSkew(session, sendingTimeMsg(now.Add(-10*time.Minute))); err == nil {
+		t.Error("expected an error for a message 10m in the past")
+	}
+}
+
+func TestCheckSendingTimeSkewTooFarInFuture(t *testing.T) {
+	now := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	session := &session{clock: func() time.Time { return now }}
+
+	if err := checkSendingTimeSkew(session, sendingTimeMsg(now.Add(1*time.Minute))); err == nil {
+		t.Error("expected an error for a message 1m in the future")
+	}
+}
+
+func TestCheckSendingTimeSkewSkippedPerSession(t *testing.T) {
+	now := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	session := &session{clock: func() time.Time { return now }, SkipSendingTimeSkewCheck: true}
+
+	if err := checkSendingTimeSkew(session, sendingTimeMsg(now.Add(-time.Hour))); err != nil {
+		t.Errorf("expected SkipSendingTimeSkewCheck to bypass the check, got %v", err)
+	}
+}
+
+func TestCheckSendingTimeSkewRespectsConfiguredWindow(t *testing.T) {
+	now := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	session := &session{
+		clock:                    func() time.Time { return now },
+		MaxSendingTimeSkewPast:   time.Hour,
+		MaxSendingTimeSkewFuture: time.Hour,
+	}
+
+	if err := checkSendingTimeSkew(session, sendingTimeMsg(now.Add(-45*time.Minute))); err != nil {
+		t.Errorf("expected widened MaxSendingTimeSkewPast to allow a 45m-old message, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package verify
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// timeoutEvents are the internal.Event values randomStep draws Timeout steps from.
+var timeoutEvents = []internal.Event{internal.LogonTimeout}
+
+// beginStrings are the FIX versions randomStep draws session-level messages from, per FIX
+// 4.2/4.4/5.0 message schemas.
+var beginStrings = []string{"FIX.4.2", "FIX.4.4", "FIXT.1.1"}
+
+// randomStep produces one random VerifyStep: either an inbound session-level Message (Logon,
+// Logout, Heartbeat, or TestRequest), a Timeout event, or a Stop.
+func randomStep(rnd *rand.Rand) quickfix.VerifyStep {
+	switch rnd.Intn(5) {
+	case 0:
+		return quickfix.VerifyStep{FixMsgIn: randomLogon(rnd)}
+	case 1:
+		return quickfix.VerifyStep{FixMsgIn: randomLogout(rnd)}
+	case 2:
+		return quickfix.VerifyStep{FixMsgIn: randomAdminMessage(rnd)}
+	case 3:
+		return quickfix.VerifyStep{Timeout: timeoutEvents[rnd.Intn(len(timeoutEvents))]}
+	default:
+		return quickfix.VerifyStep{Stop: true}
+	}
+}
+
+func randomLogon(rnd *rand.Rand) *quickfix.Message {
+	msg := newAdminMessage(rnd, "A")
+	msg.Body.SetInt(quickfix.TagHeartBtInt, 10+rnd.Intn(50))
+	return msg
+}
+
+func randomLogout(rnd *rand.Rand) *quickfix.Message {
+	msg := newAdminMessage(rnd, "5")
+
+	reasons := []string{
+		fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", 2+rnd.Intn(20), 1),
+		"Password expired",
+		"Session not found",
+		"Maximum number of sessions reached",
+		"Other",
+	}
+	msg.Body.SetString(quickfix.TagText, reasons[rnd.Intn(len(reasons))])
+
+	return msg
+}
+
+func randomAdminMessage(rnd *rand.Rand) *quickfix.Message {
+	if rnd.Intn(2) == 0 {
+		return newAdminMessage(rnd, "0") // Heartbeat
+	}
+	return newAdminMessage(rnd, "1") // TestRequest
+}
+
+func newAdminMessage(rnd *rand.Rand, msgType string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.TagBeginString, beginStrings[rnd.Intn(len(beginStrings))])
+	msg.Header.SetString(quickfix.TagMsgType, msgType)
+	msg.Header.SetUTCTimestamp(quickfix.TagSendingTime, randomSendingTime(rnd))
+	return msg
+}
+
+// randomSendingTime returns a SendingTime(52) a few seconds either side of now, comfortably
+// inside checkSendingTimeSkew's default window, with a little jitter so generated messages aren't
+// all stamped identically. Without this, every synthetic Logon fails the skew check before
+// handleLogon runs, and the harness could never reach In Session.
+func randomSendingTime(rnd *rand.Rand) time.Time {
+	jitter := time.Duration(rnd.Intn(10)-5) * time.Second
+	return time.Now().Add(jitter)
+}
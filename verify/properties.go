@@ -0,0 +1,180 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package verify
+
+import (
+	"fmt"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// BuiltinProperties returns the state-machine invariants Run always checks, in addition to
+// whatever Models the caller supplies. Invariants that need live access to a session's
+// MessageStore rather than just the trace (e.g. SeqNumMonotonicModel) are Models instead, since
+// they must be bound to the session under test.
+func BuiltinProperties() []Property {
+	return []Property{
+		propertyLogoutNeverAdvancesToInSession,
+		propertyInSessionOnlyFollowsLogon,
+	}
+}
+
+// (a) Receiving a Logout while in Logon State never transitions straight to In Session: a Logout
+// step from Logon State drops to Latent State (scheduling a logon retry on the way for some
+// reasons), but never reaches In Session directly off the back of a Logout.
+var propertyLogoutNeverAdvancesToInSession = Property{
+	Name: "logout-in-logon-state-never-advances-to-in-session",
+	Check: func(trace Trace) error {
+		for i, entry := range trace {
+			if i == 0 || !isLogout(entry.Step) || trace[i-1].State != quickfix.VerifyStateLogon {
+				continue
+			}
+
+			if entry.State == quickfix.VerifyStateInSession {
+				return fmt.Errorf("step %d: Logout received in Logon State transitioned to In Session", i)
+			}
+		}
+
+		return nil
+	},
+}
+
+// (e) No path from Logon State ever reaches In Session without the step immediately before it
+// having been a successful Logon FixMsgIn sent from Logon State.
+var propertyInSessionOnlyFollowsLogon = Property{
+	Name: "in-session-only-follows-successful-logon",
+	Check: func(trace Trace) error {
+		for i, entry := range trace {
+			if entry.State != quickfix.VerifyStateInSession {
+				continue
+			}
+
+			// NewVerifySession starts every session in Logon State.
+			prev := quickfix.VerifyStateLogon
+			if i > 0 {
+				prev = trace[i-1].State
+			}
+
+			// Only a transition into In Session is checked; steps that stay In Session are not.
+			if prev == quickfix.VerifyStateInSession {
+				continue
+			}
+
+			if prev != quickfix.VerifyStateLogon || !isLogon(entry.Step) {
+				return fmt.Errorf("step %d: reached In Session without a preceding successful Logon from Logon State", i)
+			}
+		}
+
+		return nil
+	},
+}
+
+// ForceSeqNumOnlyInLogonModel checks property (b): NextSenderMsgSeqNum only ever jumps ahead
+// (advances by more than one step's worth) immediately after a Logout received while in Logon
+// State, i.e. the forced-seqnum recovery path never fires off the back of any other step. Like
+// SeqNumMonotonicModel, it needs live access to the session's store, so the caller constructs one
+// per session under test and passes it in via Run's models argument.
+type ForceSeqNumOnlyInLogonModel struct {
+	NextSenderMsgSeqNum func() (int, error)
+
+	last int
+	seen bool
+}
+
+// Check implements Model.
+func (m *ForceSeqNumOnlyInLogonModel) Check(trace Trace) error {
+	if len(trace) == 0 {
+		return nil
+	}
+
+	sender, err := m.NextSenderMsgSeqNum()
+	if err != nil {
+		return err
+	}
+
+	if m.seen && sender > m.last+1 {
+		last := trace[len(trace)-1]
+		if !(isLogout(last.Step) && len(trace) >= 2 && trace[len(trace)-2].State == quickfix.VerifyStateLogon) {
+			return fmt.Errorf("NextSenderMsgSeqNum jumped %d -> %d without a preceding Logout from Logon State", m.last, sender)
+		}
+	}
+
+	m.last, m.seen = sender, true
+	return nil
+}
+
+// Property (c), that each RejectLogonReason increments NextTargetMsgSeqNum according to its own
+// incrNextTargetMsgSeqNum() exactly, is not checked here: exercising it would require the
+// generator to synthesize Logons that trip each specific rejection (bad credentials, CompID
+// mismatch, ...), which means reimplementing handleLogon's validation in the generator. It is
+// instead covered directly against shutdownLogonReject in the quickfix package's own tests.
+
+// SeqNumMonotonicModel checks property (d): after any transition to Latent State, the store's
+// NextSenderMsgSeqNum and NextTargetMsgSeqNum are monotonic non-decreasing. Unlike the stateless
+// built-in Properties, it needs live access to the session's store, so Run's caller constructs
+// one per session under test and passes it in via the models argument.
+type SeqNumMonotonicModel struct {
+	NextSenderMsgSeqNum func() (int, error)
+	NextTargetMsgSeqNum func() (int, error)
+
+	lastSender, lastTarget int
+	seen                   bool
+}
+
+// Check implements Model.
+func (m *SeqNumMonotonicModel) Check(trace Trace) error {
+	if len(trace) == 0 || trace[len(trace)-1].State != quickfix.VerifyStateLatent {
+		return nil
+	}
+
+	sender, err := m.NextSenderMsgSeqNum()
+	if err != nil {
+		return err
+	}
+
+	target, err := m.NextTargetMsgSeqNum()
+	if err != nil {
+		return err
+	}
+
+	if m.seen {
+		if sender < m.lastSender {
+			return fmt.Errorf("NextSenderMsgSeqNum decreased across Latent State: %d -> %d", m.lastSender, sender)
+		}
+		if target < m.lastTarget {
+			return fmt.Errorf("NextTargetMsgSeqNum decreased across Latent State: %d -> %d", m.lastTarget, target)
+		}
+	}
+
+	m.lastSender, m.lastTarget, m.seen = sender, target, true
+	return nil
+}
+
+func isLogout(step quickfix.VerifyStep) bool { return msgTypeOf(step) == "5" }
+func isLogon(step quickfix.VerifyStep) bool  { return msgTypeOf(step) == "A" }
+
+func msgTypeOf(step quickfix.VerifyStep) string {
+	if step.FixMsgIn == nil {
+		return ""
+	}
+
+	msgType, err := step.FixMsgIn.Header.GetString(quickfix.TagMsgType)
+	if err != nil {
+		return ""
+	}
+
+	return msgType
+}
@@ -0,0 +1,155 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package verify expresses the session state machine's invariants as property tests, in the
+// style of the FIX engine reference model, which verifies statements like "any outbound message
+// updates last-time-data-sent" as machine-checkable properties over a shuffled stream of inputs
+// rather than a handful of example-based unit tests.
+package verify
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Trace is the sequence of steps applied to a session over the course of one property test run,
+// paired with the state reached after each step.
+type Trace []TraceEntry
+
+// TraceEntry is one applied Step and the VerifyState it produced.
+type TraceEntry struct {
+	Step  quickfix.VerifyStep
+	State quickfix.VerifyState
+}
+
+// Model lets callers layer their own invariants over custom Application behavior on top of the
+// built-in session state-machine Properties checked by Run.
+type Model interface {
+	// Check is called after every step of a run with the trace so far. Returning an error fails
+	// the run and, like a built-in Property, triggers the shrinker.
+	Check(trace Trace) error
+}
+
+// Property is one state-machine invariant checked by Run against every trace it generates.
+type Property struct {
+	Name  string
+	Check func(trace Trace) error
+}
+
+// Config controls a Run.
+type Config struct {
+	// MaxSteps bounds how long a generated trace can be. Defaults to 50.
+	MaxSteps int
+	// MaxCount bounds how many traces Run generates before concluding the Properties hold.
+	// Defaults to testing/quick's default of 100.
+	MaxCount int
+	// Rand seeds trace generation. Defaults to a fixed seed so failures are reproducible.
+	Rand *rand.Rand
+}
+
+// Run generates shuffled traces of (sessionState, FixMsgIn|Timeout|Stop, Message) steps against
+// newSession, checking every built-in Property and every Model newModels returns after each step.
+// newModels is called once per generated trace, alongside newSession, so Models with per-session
+// state (SeqNumMonotonicModel, ForceSeqNumOnlyInLogonModel) don't carry stale state over from the
+// previous trace's session. On failure it shrinks the failing step sequence by dropping one step
+// at a time for as long as the shorter sequence still fails, and reports the result: a failing
+// sequence from which no single step can be removed.
+func Run(newSession func() *quickfix.VerifySession, newModels func() []Model, cfg Config) error {
+	if cfg.MaxSteps <= 0 {
+		cfg.MaxSteps = 50
+	}
+
+	// traceSteps.Generate is called reflectively by quick.Check with no way to thread cfg through,
+	// so stash the bound here. quick.Check runs one case at a time, so this is safe.
+	maxStepsForGenerate = cfg.MaxSteps
+	defer func() { maxStepsForGenerate = defaultMaxSteps }()
+
+	properties := BuiltinProperties()
+
+	check := func(steps traceSteps) bool {
+		session := newSession()
+		models := newModels()
+		trace := make(Trace, 0, len(steps))
+
+		for _, step := range steps {
+			state := quickfix.VerifyApply(session, step)
+			trace = append(trace, TraceEntry{Step: step, State: state})
+
+			for _, p := range properties {
+				if err := p.Check(trace); err != nil {
+					return false
+				}
+			}
+
+			for _, m := range models {
+				if err := m.Check(trace); err != nil {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	if cfg.Rand == nil {
+		cfg.Rand = rand.New(rand.NewSource(1))
+	}
+
+	qcfg := &quick.Config{MaxCount: cfg.MaxCount, Rand: cfg.Rand}
+	if err := quick.Check(check, qcfg); err != nil {
+		if cerr, ok := err.(*quick.CheckError); ok {
+			steps := cerr.In[0].(traceSteps)
+			for i := 0; i < len(steps); {
+				candidate := append(append(traceSteps{}, steps[:i]...), steps[i+1:]...)
+				if check(candidate) {
+					i++
+				} else {
+					steps = candidate
+				}
+			}
+			return fmt.Errorf("property violated after %d cases, minimal failing sequence: %v", cerr.Count, steps)
+		}
+		return err
+	}
+
+	return nil
+}
+
+const defaultMaxSteps = 50
+
+var maxStepsForGenerate = defaultMaxSteps
+
+// traceSteps is the generated input to one Run iteration. It implements quick.Generator so
+// testing/quick can generate it; Run itself shrinks a failing one.
+type traceSteps []quickfix.VerifyStep
+
+// Generate produces a random, bounded-length sequence of steps drawn from FIX 4.2/4.4/5.0 Logon,
+// Logout, Heartbeat and TestRequest messages, plus Timeout and Stop events.
+func (traceSteps) Generate(rnd *rand.Rand, size int) reflect.Value {
+	if size > maxStepsForGenerate {
+		size = maxStepsForGenerate
+	}
+
+	steps := make(traceSteps, rnd.Intn(size+1))
+	for i := range steps {
+		steps[i] = randomStep(rnd)
+	}
+
+	return reflect.ValueOf(steps)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package verify
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// fakeApplication embeds quickfix.Application so it satisfies the interface without implementing
+// every method; InSession is the only one the Logon State transition under test actually calls.
+type fakeApplication struct{ quickfix.Application }
+
+func (fakeApplication) InSession(sessionID quickfix.SessionID) {}
+
+// fakeLog embeds quickfix.Log so it satisfies the interface without implementing every method;
+// OnEvent/OnEventf are the only ones the code under test calls.
+type fakeLog struct{ quickfix.Log }
+
+func (fakeLog) OnEvent(string)                  {}
+func (fakeLog) OnEventf(string, ...interface{}) {}
+
+// fakeStore embeds quickfix.MessageStore so it satisfies the interface without implementing every
+// method, tracking just the sequence numbers SeqNumMonotonicModel and
+// ForceSeqNumOnlyInLogonModel observe.
+type fakeStore struct {
+	quickfix.MessageStore
+	sender, target int
+}
+
+func (s *fakeStore) NextSenderMsgSeqNum() (int, error)     { return s.sender, nil }
+func (s *fakeStore) NextTargetMsgSeqNum() (int, error)     { return s.target, nil }
+func (s *fakeStore) IncrNextSenderMsgSeqNum() error        { s.sender++; return nil }
+func (s *fakeStore) IncrNextTargetMsgSeqNum() error        { s.target++; return nil }
+func (s *fakeStore) SetNextSenderMsgSeqNum(next int) error { s.sender = next; return nil }
+func (s *fakeStore) SetNextTargetMsgSeqNum(next int) error { s.target = next; return nil }
+
+// TestRunBuiltinPropertiesAndModels exercises Run end to end against a real (faked-dependency)
+// session, instantiating SeqNumMonotonicModel and ForceSeqNumOnlyInLogonModel fresh per trace so
+// properties (b) and (d) are actually checked, not just defined.
+func TestRunBuiltinPropertiesAndModels(t *testing.T) {
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	var store *fakeStore
+	newSession := func() *quickfix.VerifySession {
+		store = &fakeStore{sender: 1, target: 1}
+		return quickfix.NewVerifySession(sessionID, fakeApplication{}, store, fakeLog{})
+	}
+
+	newModels := func() []Model {
+		return []Model{
+			&SeqNumMonotonicModel{
+				NextSenderMsgSeqNum: func() (int, error) { return store.NextSenderMsgSeqNum() },
+				NextTargetMsgSeqNum: func() (int, error) { return store.NextTargetMsgSeqNum() },
+			},
+			&ForceSeqNumOnlyInLogonModel{
+				NextSenderMsgSeqNum: func() (int, error) { return store.NextSenderMsgSeqNum() },
+			},
+		}
+	}
+
+	cfg := Config{MaxSteps: 20, MaxCount: 50, Rand: rand.New(rand.NewSource(1))}
+	if err := Run(newSession, newModels, cfg); err != nil {
+		t.Fatal(err)
+	}
+}
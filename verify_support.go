@@ -0,0 +1,113 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "github.com/quickfixgo/quickfix/internal"
+
+// The types and functions in this file back the github.com/quickfixgo/quickfix/verify property
+// test harness. They exist so that harness can drive a session's state machine and assert on its
+// invariants without reaching into unexported state types, which a separate package cannot do.
+// They are not part of the stable public API and may change without notice between releases.
+
+// VerifySession aliases the unexported session type so the verify.Model harness, which lives in
+// a separate package, has a name to declare session handles with. Its fields and most of its
+// methods remain inaccessible outside this package; only the functions in this file operate on it.
+type VerifySession = session
+
+// VerifyState names a sessionState for use by verify.Model implementations, which live outside
+// this package and therefore cannot compare against the unexported state types directly.
+type VerifyState string
+
+// Names of the built-in sessionStates, for verify.Model implementations to compare VerifyState
+// against. Kept in sync with each state's String() method.
+const (
+	VerifyStateLatent    VerifyState = "Latent State"
+	VerifyStateLogon     VerifyState = "Logon State"
+	VerifyStateInSession VerifyState = "In Session"
+)
+
+// Exported aliases of this package's unexported FIX tag numbers (tagBeginString, tagMsgType,
+// ...), for the verify package to build synthetic Messages with since it cannot reference the
+// unexported constants directly.
+const (
+	TagBeginString           = tagBeginString
+	TagMsgType               = tagMsgType
+	TagText                  = tagText
+	TagHeartBtInt            = tagHeartBtInt
+	TagSendingTime           = tagSendingTime
+	TagSessionRejectReason   = tagSessionRejectReason
+	TagNextExpectedMsgSeqNum = tagNextExpectedMsgSeqNum
+)
+
+// VerifyStep is one input applied to a session's state machine by VerifyApply.
+type VerifyStep struct {
+	FixMsgIn *Message
+	Timeout  internal.Event
+	Stop     bool
+}
+
+// VerifyApply drives step through session's current state and returns the name of the state
+// reached.
+func VerifyApply(session *VerifySession, step VerifyStep) VerifyState {
+	var next sessionState
+	switch {
+	case step.FixMsgIn != nil:
+		next = session.State.FixMsgIn(session, step.FixMsgIn)
+	case step.Stop:
+		next = session.State.Stop(session)
+	default:
+		next = session.State.Timeout(session, step.Timeout)
+	}
+
+	session.State = next
+	return VerifyState(next.String())
+}
+
+// VerifyStateName returns the name of session's current state.
+func VerifyStateName(session *VerifySession) VerifyState {
+	return VerifyState(session.State.String())
+}
+
+// VerifyNextSenderMsgSeqNum and VerifyNextTargetMsgSeqNum expose session.store's sequence numbers
+// so verify.Model implementations can assert on their monotonicity without depending on the
+// unexported MessageStore field directly.
+func VerifyNextSenderMsgSeqNum(session *VerifySession) (int, error) {
+	return session.store.NextSenderMsgSeqNum()
+}
+
+func VerifyNextTargetMsgSeqNum(session *VerifySession) (int, error) {
+	return session.store.NextTargetMsgSeqNum()
+}
+
+// VerifyLogonForceSenderMsgSeqNum exposes session.LogonForceSenderMsgSeqNum so verify.Model
+// implementations can assert on the force-vs-retry logon recovery path without depending on the
+// unexported field directly.
+func VerifyLogonForceSenderMsgSeqNum(session *VerifySession) bool {
+	return session.LogonForceSenderMsgSeqNum
+}
+
+// NewVerifySession constructs a session for the verify harness, in Logon State as a freshly
+// connected but not-yet-logged-on session would be, wired up with the given sessionID,
+// Application, and MessageStore.
+func NewVerifySession(sessionID SessionID, app Application, store MessageStore, log Log) *VerifySession {
+	return &session{
+		sessionID:   sessionID,
+		application: app,
+		store:       store,
+		log:         log,
+		State:       logonState{},
+	}
+}